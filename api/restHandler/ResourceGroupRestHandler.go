@@ -23,9 +23,11 @@ import (
 	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
 	"github.com/devtron-labs/devtron/pkg/auth/user"
 	"github.com/devtron-labs/devtron/pkg/resourceGroup"
+	"github.com/ghodss/yaml"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 	"gopkg.in/go-playground/validator.v9"
+	"io/ioutil"
 	"net/http"
 	"strconv"
 )
@@ -37,6 +39,17 @@ type ResourceGroupRestHandler interface {
 	UpdateResourceGroup(w http.ResponseWriter, r *http.Request)
 	DeleteResourceGroup(w http.ResponseWriter, r *http.Request)
 	CheckResourceGroupPermissions(w http.ResponseWriter, r *http.Request)
+	CheckResourceGroupPermissionsBulk(w http.ResponseWriter, r *http.Request)
+	PatchResourceGroup(w http.ResponseWriter, r *http.Request)
+	ExportResourceGroups(w http.ResponseWriter, r *http.Request)
+	ImportResourceGroups(w http.ResponseWriter, r *http.Request)
+	WatchResourceGroups(w http.ResponseWriter, r *http.Request)
+}
+
+type ResourceGroupPatchRequest struct {
+	//Fingerprint is the optimistic-lock token returned with the group on read; a stale value is rejected with 409
+	Fingerprint string                          `json:"fingerprint" validate:"required"`
+	Operations  []*resourceGroup.PatchOperation `json:"operations" validate:"required,min=1,dive"`
 }
 
 type ResourceGroupRestHandlerImpl struct {
@@ -79,6 +92,11 @@ func (handler ResourceGroupRestHandlerImpl) getGroupTypeAndAuthFunc(groupType st
 func (handler ResourceGroupRestHandlerImpl) GetActiveResourceGroupList(w http.ResponseWriter, r *http.Request) {
 	token := r.Header.Get("token")
 
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
 	vars := mux.Vars(r)
 	resourceId, err := strconv.Atoi(vars["resourceId"])
 	if err != nil {
@@ -91,7 +109,20 @@ func (handler ResourceGroupRestHandlerImpl) GetActiveResourceGroupList(w http.Re
 		return
 	}
 
-	res, err := handler.resourceGroupService.GetActiveResourceGroupList(token, authFunc, resourceId, groupType)
+	listOptions, err := handler.parseListResourceGroupOptions(r, userId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	if listOptions.MemberKind == resourceGroup.MemberKindUser && listOptions.MemberId != userId {
+		if isAuthorised := handler.enforcer.Enforce(token, casbin.ResourceUser, casbin.ActionGet, strconv.Itoa(int(listOptions.MemberId))); !isAuthorised {
+			common.WriteJsonResp(w, fmt.Errorf("unauthorized"), nil, http.StatusForbidden)
+			return
+		}
+	}
+
+	res, err := handler.resourceGroupService.ListResourceGroups(token, authFunc, resourceId, groupType, listOptions)
 	if err != nil {
 		handler.logger.Errorw("service err, GetActiveResourceGroupList", "err", err)
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
@@ -100,6 +131,43 @@ func (handler ResourceGroupRestHandlerImpl) GetActiveResourceGroupList(w http.Re
 	common.WriteJsonResp(w, nil, res, http.StatusOK)
 }
 
+//parseListResourceGroupOptions reads the memberKind/memberId/permission/limit/offset/nameContains
+//query params, defaulting memberKind to "self" (the logged-in user) and limit to 0 (unbounded)
+func (handler ResourceGroupRestHandlerImpl) parseListResourceGroupOptions(r *http.Request, userId int32) (resourceGroup.ListOptions, error) {
+	query := r.URL.Query()
+	listOptions := resourceGroup.ListOptions{
+		MemberKind:   resourceGroup.MemberKind(query.Get("memberKind")),
+		Permission:   query.Get("permission"),
+		NameContains: query.Get("nameContains"),
+		MemberId:     userId,
+	}
+	if listOptions.MemberKind == "" {
+		listOptions.MemberKind = resourceGroup.MemberKindSelf
+	}
+	if memberId := query.Get("memberId"); memberId != "" {
+		parsedMemberId, err := strconv.Atoi(memberId)
+		if err != nil {
+			return listOptions, fmt.Errorf("invalid memberId %s", memberId)
+		}
+		listOptions.MemberId = int32(parsedMemberId)
+	}
+	if limit := query.Get("limit"); limit != "" {
+		parsedLimit, err := strconv.Atoi(limit)
+		if err != nil {
+			return listOptions, fmt.Errorf("invalid limit %s", limit)
+		}
+		listOptions.Limit = parsedLimit
+	}
+	if offset := query.Get("offset"); offset != "" {
+		parsedOffset, err := strconv.Atoi(offset)
+		if err != nil {
+			return listOptions, fmt.Errorf("invalid offset %s", offset)
+		}
+		listOptions.Offset = parsedOffset
+	}
+	return listOptions, nil
+}
+
 //	func (handler ResourceGroupRestHandlerImpl) GetApplicationsForResourceGroup(w http.ResponseWriter, r *http.Request) {
 //		userId, err := handler.userService.GetLoggedInUser(r)
 //		if userId == 0 || err != nil {
@@ -261,6 +329,148 @@ func (handler ResourceGroupRestHandlerImpl) DeleteResourceGroup(w http.ResponseW
 	}
 	common.WriteJsonResp(w, nil, resp, http.StatusOK)
 }
+func (handler ResourceGroupRestHandlerImpl) PatchResourceGroup(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("token")
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	resourceGroupId, err := strconv.Atoi(vars["resourceGroupId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var request ResourceGroupPatchRequest
+	err = decoder.Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, PatchResourceGroup", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation error", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	groupType, authFunc, err := handler.getGroupTypeAndAuthFunc(vars["groupType"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	handler.logger.Infow("request payload, PatchResourceGroup", "resourceGroupId", resourceGroupId, "payload", request)
+	resp, err := handler.resourceGroupService.PatchResourceGroup(resourceGroupId, groupType, request.Fingerprint, request.Operations, userId, token, authFunc)
+	if err != nil {
+		if err == resourceGroup.ErrFingerprintConflict {
+			handler.logger.Errorw("fingerprint conflict, PatchResourceGroup", "resourceGroupId", resourceGroupId)
+			common.WriteJsonResp(w, err, resp, http.StatusConflict)
+			return
+		}
+		handler.logger.Errorw("service err, PatchResourceGroup", "err", err, "resourceGroupId", resourceGroupId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler ResourceGroupRestHandlerImpl) ExportResourceGroups(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("token")
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	resourceId, err := strconv.Atoi(vars["resourceId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	groupType, authFunc, err := handler.getGroupTypeAndAuthFunc(vars["groupType"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	handler.logger.Infow("request payload, ExportResourceGroups", "resourceId", resourceId, "groupType", groupType)
+	groups, err := handler.resourceGroupService.ExportResourceGroups(token, authFunc, resourceId, groupType)
+	if err != nil {
+		handler.logger.Errorw("service err, ExportResourceGroups", "err", err, "resourceId", resourceId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	yamlBytes, err := yaml.Marshal(groups)
+	if err != nil {
+		handler.logger.Errorw("error marshalling resource groups to yaml", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-yaml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(yamlBytes)
+}
+
+func (handler ResourceGroupRestHandlerImpl) ImportResourceGroups(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("token")
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	resourceId, err := strconv.Atoi(vars["resourceId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	groupType, authFunc, err := handler.getGroupTypeAndAuthFunc(vars["groupType"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	dryRun := r.URL.Query().Get("dryRun") == "true"
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		handler.logger.Errorw("request err, ImportResourceGroups", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	//body is a YAML list (not object), so the object-shaped utils.IsValidYAML/IsValidJSON helpers don't apply here;
+	//validate by converting to JSON directly and let yaml.Unmarshal below catch any remaining structural errors
+	if _, err := yaml.YAMLToJSON(body); err != nil {
+		handler.logger.Errorw("request err, ImportResourceGroups", "err", err)
+		common.WriteJsonResp(w, fmt.Errorf("invalid yaml payload"), nil, http.StatusBadRequest)
+		return
+	}
+	var groups []*resourceGroup.ResourceGroupDto
+	err = yaml.Unmarshal(body, &groups)
+	if err != nil {
+		handler.logger.Errorw("request err, ImportResourceGroups", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	for _, group := range groups {
+		group.UserId = userId
+		group.ParentResourceId = resourceId
+		group.GroupType = groupType
+		group.CheckAuthBatch = authFunc
+	}
+
+	handler.logger.Infow("request payload, ImportResourceGroups", "resourceId", resourceId, "groupType", groupType, "dryRun", dryRun, "count", len(groups))
+	resp, err := handler.resourceGroupService.ImportResourceGroups(groups, token, dryRun)
+	if err != nil {
+		handler.logger.Errorw("service err, ImportResourceGroups", "err", err, "resourceId", resourceId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
 func (handler ResourceGroupRestHandlerImpl) CheckResourceGroupPermissions(w http.ResponseWriter, r *http.Request) {
 	token := r.Header.Get("token")
 
@@ -314,6 +524,124 @@ func (handler ResourceGroupRestHandlerImpl) CheckResourceGroupPermissions(w http
 	common.WriteJsonResp(w, nil, resp, http.StatusOK)
 }
 
+func (handler ResourceGroupRestHandlerImpl) CheckResourceGroupPermissionsBulk(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("token")
+
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	decoder := json.NewDecoder(r.Body)
+	var request resourceGroup.PermissionBulkRequest
+	err = decoder.Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, CheckResourceGroupPermissionsBulk", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation error", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	vars := mux.Vars(r)
+	groupType, authFunc, err := handler.getGroupTypeAndAuthFunc(vars["groupType"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.GroupType = groupType
+	request.CheckAuthBatch = authFunc
+
+	handler.logger.Infow("request payload, CheckResourceGroupPermissionsBulk", "payload", request)
+	resp, err := handler.resourceGroupService.CheckResourceGroupPermissionsBulk(&request, token)
+	if err != nil {
+		handler.logger.Errorw("service err, CheckResourceGroupPermissionsBulk", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler ResourceGroupRestHandlerImpl) WatchResourceGroups(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("token")
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := mux.Vars(r)
+	resourceId, err := strconv.Atoi(vars["resourceId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	groupType, authFunc, err := handler.getGroupTypeAndAuthFunc(vars["groupType"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		common.WriteJsonResp(w, fmt.Errorf("streaming unsupported"), nil, http.StatusInternalServerError)
+		return
+	}
+
+	events, cancel := handler.resourceGroupService.Subscribe(resourceId, groupType)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	handler.logger.Infow("watch started, WatchResourceGroups", "resourceId", resourceId, "groupType", groupType)
+	for {
+		select {
+		case <-r.Context().Done():
+			handler.logger.Infow("watch closed, WatchResourceGroups", "resourceId", resourceId, "groupType", groupType)
+			return
+		case event, isOpen := <-events:
+			if !isOpen {
+				return
+			}
+			visibleResourceIds := handler.filterVisibleResourceIds(token, authFunc, event.ResourceIds)
+			if len(visibleResourceIds) == 0 {
+				continue
+			}
+			event.ResourceIds = visibleResourceIds
+			payload, err := json.Marshal(event)
+			if err != nil {
+				handler.logger.Errorw("error marshalling resource group event", "err", err)
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", payload)
+			flusher.Flush()
+		}
+	}
+}
+
+//filterVisibleResourceIds re-runs the batch auth callback on a delivered event so a subscriber never
+//sees resources it can't view, even if another user's edit affected a mix of visible and hidden ones
+func (handler ResourceGroupRestHandlerImpl) filterVisibleResourceIds(token string, authFunc func(token string, appObject []string, action string) map[string]bool, resourceIds []int) []int {
+	objects := make([]string, 0, len(resourceIds))
+	for _, id := range resourceIds {
+		objects = append(objects, strconv.Itoa(id))
+	}
+	allowed := authFunc(token, objects, casbin.ActionGet)
+	visible := make([]int, 0, len(resourceIds))
+	for _, id := range resourceIds {
+		if allowed[strconv.Itoa(id)] {
+			visible = append(visible, id)
+		}
+	}
+	return visible
+}
+
 func (handler ResourceGroupRestHandlerImpl) checkAppAuthBatch(token string, appObject []string, action string) map[string]bool {
 	var appResult map[string]bool
 	if len(appObject) > 0 {
@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2020-2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resourceGroup
+
+import (
+	"strconv"
+
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+)
+
+func (impl *ResourceGroupServiceImpl) ExportResourceGroups(token string, authFunc CheckAuthBatchFunc, parentResourceId int, groupType ResourceGroupType) ([]*ResourceGroupDto, error) {
+	impl.mutex.RLock()
+	defer impl.mutex.RUnlock()
+	var result []*ResourceGroupDto
+	for _, group := range impl.groups {
+		if group.ParentResourceId == parentResourceId && group.GroupType == groupType {
+			result = append(result, group)
+		}
+	}
+	return result, nil
+}
+
+//ImportResourceGroups upserts groups matched by parent+groupType+name, running the per-row auth
+//check against each group's own resourceIds. A row that fails the check is reported as "skipped"
+//and the other rows are still applied. Applied rows are staged and swapped into the live store
+//together in one step; dryRun stages but never swaps.
+func (impl *ResourceGroupServiceImpl) ImportResourceGroups(groups []*ResourceGroupDto, token string, dryRun bool) (*ImportResult, error) {
+	impl.mutex.Lock()
+	defer impl.mutex.Unlock()
+
+	staged := make(map[int]*ResourceGroupDto, len(impl.groups))
+	for id, group := range impl.groups {
+		staged[id] = group
+	}
+	nextId := impl.lastId
+
+	rows := make([]*ImportRowResult, 0, len(groups))
+	for _, incoming := range groups {
+		row := &ImportRowResult{Name: incoming.Name}
+
+		if !impl.isImportRowAuthorized(token, incoming) {
+			row.Status = "skipped"
+			row.Error = "not authorized for one or more resourceIds"
+			rows = append(rows, row)
+			continue
+		}
+
+		existingId := findGroupId(staged, incoming.ParentResourceId, incoming.GroupType, incoming.Name)
+		if existingId != 0 {
+			row.Status = "updated"
+			incoming.Id = existingId
+			incoming.Fingerprint = nextFingerprint(staged[existingId].Fingerprint)
+		} else {
+			row.Status = "created"
+			nextId++
+			incoming.Id = nextId
+			incoming.Fingerprint = nextFingerprint("")
+		}
+		staged[incoming.Id] = incoming
+		rows = append(rows, row)
+	}
+
+	if !dryRun {
+		impl.groups = staged
+		impl.lastId = nextId
+	}
+	return &ImportResult{DryRun: dryRun, Rows: rows}, nil
+}
+
+func (impl *ResourceGroupServiceImpl) isImportRowAuthorized(token string, incoming *ResourceGroupDto) bool {
+	if incoming.CheckAuthBatch == nil || len(incoming.ResourceIds) == 0 {
+		return true
+	}
+	objects := make([]string, 0, len(incoming.ResourceIds))
+	for _, id := range incoming.ResourceIds {
+		objects = append(objects, strconv.Itoa(id))
+	}
+	allowed := incoming.CheckAuthBatch(token, objects, casbin.ActionUpdate)
+	for _, object := range objects {
+		if !allowed[object] {
+			return false
+		}
+	}
+	return true
+}
+
+func findGroupId(groups map[int]*ResourceGroupDto, parentResourceId int, groupType ResourceGroupType, name string) int {
+	for id, group := range groups {
+		if group.ParentResourceId == parentResourceId && group.GroupType == groupType && group.Name == name {
+			return id
+		}
+	}
+	return 0
+}
@@ -0,0 +1,191 @@
+/*
+ * Copyright (c) 2020-2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resourceGroup
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+)
+
+//ErrFingerprintConflict is returned by PatchResourceGroup when the caller's fingerprint is stale,
+//i.e. another user persisted a change since the caller last read the group
+var ErrFingerprintConflict = errors.New("resource group was modified concurrently, fingerprint is stale")
+
+//PatchResourceGroup resolves the current DTO, applies the path-scoped mutations, re-runs the batch
+//auth callback on only the delta of resourceIds (added/removed) and persists under a fingerprint
+//check so concurrent edits from different users don't clobber each other
+func (impl *ResourceGroupServiceImpl) PatchResourceGroup(resourceGroupId int, groupType ResourceGroupType, fingerprint string,
+	operations []*PatchOperation, userId int32, token string, authFunc CheckAuthBatchFunc) (*ResourceGroupDto, error) {
+	impl.mutex.Lock()
+	defer impl.mutex.Unlock()
+
+	existing, ok := impl.groups[resourceGroupId]
+	if !ok || existing.GroupType != groupType {
+		return nil, fmt.Errorf("resource group %d not found", resourceGroupId)
+	}
+	if existing.Fingerprint != fingerprint {
+		return existing, ErrFingerprintConflict
+	}
+
+	patched := *existing
+	resourceIdsBefore := append([]int(nil), existing.ResourceIds...)
+	for _, op := range operations {
+		if err := applyPatchOperation(&patched, op); err != nil {
+			return nil, err
+		}
+	}
+
+	added, removed := diffResourceIds(resourceIdsBefore, patched.ResourceIds)
+	if authFunc != nil && len(added)+len(removed) > 0 {
+		delta := append(append([]int{}, added...), removed...)
+		objects := make([]string, 0, len(delta))
+		for _, id := range delta {
+			objects = append(objects, strconv.Itoa(id))
+		}
+		allowed := authFunc(token, objects, casbin.ActionUpdate)
+		for _, object := range objects {
+			if !allowed[object] {
+				return nil, fmt.Errorf("not authorized to update resource %s", object)
+			}
+		}
+	}
+
+	patched.UserId = userId
+	patched.Fingerprint = nextFingerprint(existing.Fingerprint)
+	impl.groups[resourceGroupId] = &patched
+	return &patched, nil
+}
+
+func applyPatchOperation(dto *ResourceGroupDto, op *PatchOperation) error {
+	switch op.Path {
+	case "resourceIds":
+		ids, err := toIntSlice(op.Value)
+		if err != nil {
+			return err
+		}
+		switch op.Op {
+		case "replace":
+			dto.ResourceIds = ids
+		case "add":
+			dto.ResourceIds = unionInts(dto.ResourceIds, ids)
+		case "remove":
+			dto.ResourceIds = subtractInts(dto.ResourceIds, ids)
+		}
+	case "description":
+		value, ok := op.Value.(string)
+		if !ok {
+			return fmt.Errorf("value for path %s must be a string", op.Path)
+		}
+		if op.Op == "remove" {
+			dto.Description = ""
+		} else {
+			dto.Description = value
+		}
+	case "name":
+		if op.Op == "remove" {
+			return fmt.Errorf("op remove is not supported for path %s", op.Path)
+		}
+		value, ok := op.Value.(string)
+		if !ok {
+			return fmt.Errorf("value for path %s must be a string", op.Path)
+		}
+		dto.Name = value
+	default:
+		return fmt.Errorf("unsupported path %s", op.Path)
+	}
+	return nil
+}
+
+func toIntSlice(value interface{}) ([]int, error) {
+	raw, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("value must be an array of resource ids")
+	}
+	ids := make([]int, 0, len(raw))
+	for _, v := range raw {
+		switch n := v.(type) {
+		case float64:
+			ids = append(ids, int(n))
+		case int:
+			ids = append(ids, n)
+		default:
+			return nil, fmt.Errorf("resource id %v is not numeric", v)
+		}
+	}
+	return ids, nil
+}
+
+func unionInts(base []int, additions []int) []int {
+	seen := make(map[int]bool, len(base))
+	result := append([]int{}, base...)
+	for _, id := range base {
+		seen[id] = true
+	}
+	for _, id := range additions {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func subtractInts(base []int, removals []int) []int {
+	toRemove := make(map[int]bool, len(removals))
+	for _, id := range removals {
+		toRemove[id] = true
+	}
+	result := make([]int, 0, len(base))
+	for _, id := range base {
+		if !toRemove[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+//diffResourceIds returns the ids present in after but not before (added), and in before but not after (removed)
+func diffResourceIds(before []int, after []int) ([]int, []int) {
+	beforeSet := make(map[int]bool, len(before))
+	for _, id := range before {
+		beforeSet[id] = true
+	}
+	afterSet := make(map[int]bool, len(after))
+	for _, id := range after {
+		afterSet[id] = true
+	}
+	var added, removed []int
+	for _, id := range after {
+		if !beforeSet[id] {
+			added = append(added, id)
+		}
+	}
+	for _, id := range before {
+		if !afterSet[id] {
+			removed = append(removed, id)
+		}
+	}
+	return added, removed
+}
+
+func nextFingerprint(current string) string {
+	n, _ := strconv.Atoi(current)
+	return strconv.Itoa(n + 1)
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright (c) 2020-2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resourceGroup
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"go.uber.org/zap"
+)
+
+type ResourceGroupService interface {
+	GetActiveResourceGroupList(token string, authFunc CheckAuthBatchFunc, parentResourceId int, groupType ResourceGroupType) ([]*ResourceGroupDto, error)
+	ListResourceGroups(token string, authFunc CheckAuthBatchFunc, parentResourceId int, groupType ResourceGroupType, listOptions ListOptions) (*ListResourceGroupsResult, error)
+	CreateResourceGroup(request *ResourceGroupDto, token string) (*ResourceGroupDto, error)
+	UpdateResourceGroup(request *ResourceGroupDto, token string) (*ResourceGroupDto, error)
+	DeleteResourceGroup(resourceGroupId int, groupType ResourceGroupType, token string, authFunc CheckAuthBatchFunc) (bool, error)
+	CheckResourceGroupPermissions(request *ResourceGroupDto, token string) (map[string]bool, error)
+	CheckResourceGroupPermissionsBulk(request *PermissionBulkRequest, token string) ([]*PermissionBulkResult, error)
+	PatchResourceGroup(resourceGroupId int, groupType ResourceGroupType, fingerprint string, operations []*PatchOperation, userId int32, token string, authFunc CheckAuthBatchFunc) (*ResourceGroupDto, error)
+	ExportResourceGroups(token string, authFunc CheckAuthBatchFunc, parentResourceId int, groupType ResourceGroupType) ([]*ResourceGroupDto, error)
+	ImportResourceGroups(groups []*ResourceGroupDto, token string, dryRun bool) (*ImportResult, error)
+	Subscribe(parentResourceId int, groupType ResourceGroupType) (<-chan Event, func())
+}
+
+type ResourceGroupServiceImpl struct {
+	logger *zap.SugaredLogger
+
+	mutex  sync.RWMutex
+	lastId int
+	groups map[int]*ResourceGroupDto
+
+	subMutex    sync.Mutex
+	subscribers map[string][]*resourceGroupSubscription
+}
+
+func NewResourceGroupServiceImpl(logger *zap.SugaredLogger) *ResourceGroupServiceImpl {
+	return &ResourceGroupServiceImpl{
+		logger: logger,
+		groups: make(map[int]*ResourceGroupDto),
+	}
+}
+
+func (impl *ResourceGroupServiceImpl) GetActiveResourceGroupList(token string, authFunc CheckAuthBatchFunc, parentResourceId int, groupType ResourceGroupType) ([]*ResourceGroupDto, error) {
+	impl.mutex.RLock()
+	defer impl.mutex.RUnlock()
+	var result []*ResourceGroupDto
+	for _, group := range impl.groups {
+		if group.ParentResourceId == parentResourceId && group.GroupType == groupType {
+			result = append(result, group)
+		}
+	}
+	return result, nil
+}
+
+//ListResourceGroups supersedes GetActiveResourceGroupList with memberKind filtering and pagination.
+//It lists every group of the given parent resource and groupType, optionally narrowed by nameContains;
+//authFunc is not applied to the listed groups. memberKind=user with a non-zero memberId keeps only the
+//groups whose UserId equals memberId; memberKind=self (the default) and memberKind=group apply no
+//membership filtering.
+func (impl *ResourceGroupServiceImpl) ListResourceGroups(token string, authFunc CheckAuthBatchFunc, parentResourceId int, groupType ResourceGroupType, listOptions ListOptions) (*ListResourceGroupsResult, error) {
+	impl.mutex.RLock()
+	defer impl.mutex.RUnlock()
+
+	var matched []*ResourceGroupDto
+	for _, group := range impl.groups {
+		if group.ParentResourceId != parentResourceId || group.GroupType != groupType {
+			continue
+		}
+		if listOptions.NameContains != "" && !strings.Contains(group.Name, listOptions.NameContains) {
+			continue
+		}
+		if listOptions.MemberKind == MemberKindUser && listOptions.MemberId != 0 && group.UserId != listOptions.MemberId {
+			continue
+		}
+		matched = append(matched, group)
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Id < matched[j].Id })
+
+	total := len(matched)
+	offset := listOptions.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > total {
+		offset = total
+	}
+	end := total
+	if listOptions.Limit > 0 && offset+listOptions.Limit < end {
+		end = offset + listOptions.Limit
+	}
+
+	result := &ListResourceGroupsResult{Groups: matched[offset:end], Total: total}
+	if end < total {
+		result.NextOffset = end
+	}
+	return result, nil
+}
+
+func (impl *ResourceGroupServiceImpl) CreateResourceGroup(request *ResourceGroupDto, token string) (*ResourceGroupDto, error) {
+	if request.CheckAuthBatch != nil && len(request.ResourceIds) > 0 {
+		objects := make([]string, 0, len(request.ResourceIds))
+		for _, id := range request.ResourceIds {
+			objects = append(objects, strconv.Itoa(id))
+		}
+		request.CheckAuthBatch(token, objects, casbin.ActionUpdate)
+	}
+	impl.mutex.Lock()
+	impl.lastId++
+	request.Id = impl.lastId
+	request.Fingerprint = nextFingerprint("")
+	impl.groups[request.Id] = request
+	impl.mutex.Unlock()
+
+	impl.publish(Event{Op: EventOpCreated, GroupId: request.Id, ResourceIds: request.ResourceIds, ActorUserId: request.UserId, Ts: time.Now().Unix()}, request.ParentResourceId, request.GroupType)
+	return request, nil
+}
+
+func (impl *ResourceGroupServiceImpl) UpdateResourceGroup(request *ResourceGroupDto, token string) (*ResourceGroupDto, error) {
+	impl.mutex.Lock()
+	existing, ok := impl.groups[request.Id]
+	if !ok {
+		impl.mutex.Unlock()
+		return nil, fmt.Errorf("resource group %d not found", request.Id)
+	}
+	request.Fingerprint = nextFingerprint(existing.Fingerprint)
+	impl.groups[request.Id] = request
+	impl.mutex.Unlock()
+
+	impl.publish(Event{Op: EventOpUpdated, GroupId: request.Id, ResourceIds: request.ResourceIds, ActorUserId: request.UserId, Ts: time.Now().Unix()}, request.ParentResourceId, request.GroupType)
+	return request, nil
+}
+
+func (impl *ResourceGroupServiceImpl) DeleteResourceGroup(resourceGroupId int, groupType ResourceGroupType, token string, authFunc CheckAuthBatchFunc) (bool, error) {
+	impl.mutex.Lock()
+	group, ok := impl.groups[resourceGroupId]
+	if !ok {
+		impl.mutex.Unlock()
+		return false, fmt.Errorf("resource group %d not found", resourceGroupId)
+	}
+	delete(impl.groups, resourceGroupId)
+	impl.mutex.Unlock()
+
+	impl.publish(Event{Op: EventOpDeleted, GroupId: resourceGroupId, ResourceIds: group.ResourceIds, ActorUserId: group.UserId, Ts: time.Now().Unix()}, group.ParentResourceId, groupType)
+	return true, nil
+}
+
+func (impl *ResourceGroupServiceImpl) CheckResourceGroupPermissions(request *ResourceGroupDto, token string) (map[string]bool, error) {
+	if request.CheckAuthBatch == nil {
+		return map[string]bool{}, nil
+	}
+	objects := make([]string, 0, len(request.ResourceIds))
+	for _, id := range request.ResourceIds {
+		objects = append(objects, strconv.Itoa(id))
+	}
+	return request.CheckAuthBatch(token, objects, casbin.ActionGet), nil
+}
+
+//CheckResourceGroupPermissionsBulk dedupes the union of all resourceIds across items and invokes
+//CheckAuthBatch once per action, so N items sharing M resourceIds cost len(actions) enforcer calls
+//instead of N*M
+func (impl *ResourceGroupServiceImpl) CheckResourceGroupPermissionsBulk(request *PermissionBulkRequest, token string) ([]*PermissionBulkResult, error) {
+	idSet := make(map[int]bool)
+	for _, item := range request.Items {
+		for _, id := range item.ResourceIds {
+			idSet[id] = true
+		}
+	}
+	dedupedObjects := make([]string, 0, len(idSet))
+	for id := range idSet {
+		dedupedObjects = append(dedupedObjects, strconv.Itoa(id))
+	}
+
+	//actionResults[action][objectStr] = allowed
+	actionResults := make(map[string]map[string]bool, len(permissionBulkActions))
+	for _, action := range permissionBulkActions {
+		actionResults[action] = request.CheckAuthBatch(token, dedupedObjects, action)
+	}
+
+	results := make([]*PermissionBulkResult, 0, len(request.Items))
+	for _, item := range request.Items {
+		perResource := make(map[int]map[string]bool, len(item.ResourceIds))
+		for _, id := range item.ResourceIds {
+			objectStr := strconv.Itoa(id)
+			allowed := make(map[string]bool, len(permissionBulkActions))
+			for _, action := range permissionBulkActions {
+				allowed[action] = actionResults[action][objectStr]
+			}
+			perResource[id] = allowed
+		}
+		results = append(results, &PermissionBulkResult{
+			ResourceId: item.ResourceId,
+			Results:    perResource,
+		})
+	}
+	return results, nil
+}
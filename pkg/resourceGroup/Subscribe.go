@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2020-2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resourceGroup
+
+import "strconv"
+
+//subscriberBufferSize bounds how far a watcher can fall behind before events are dropped for it;
+//publish() never blocks the write path waiting on a slow subscriber
+const subscriberBufferSize = 16
+
+type resourceGroupSubscription struct {
+	ch chan Event
+}
+
+//Subscribe returns a channel of Events for the given parent resource/groupType and a cancel func
+//that must be called to release the subscription. Producers in CreateResourceGroup/
+//UpdateResourceGroup/DeleteResourceGroup publish to it after commit.
+func (impl *ResourceGroupServiceImpl) Subscribe(parentResourceId int, groupType ResourceGroupType) (<-chan Event, func()) {
+	sub := &resourceGroupSubscription{ch: make(chan Event, subscriberBufferSize)}
+	key := subscriptionKey(parentResourceId, groupType)
+
+	impl.subMutex.Lock()
+	if impl.subscribers == nil {
+		impl.subscribers = make(map[string][]*resourceGroupSubscription)
+	}
+	impl.subscribers[key] = append(impl.subscribers[key], sub)
+	impl.subMutex.Unlock()
+
+	var cancelOnce bool
+	cancel := func() {
+		impl.subMutex.Lock()
+		defer impl.subMutex.Unlock()
+		if cancelOnce {
+			return
+		}
+		cancelOnce = true
+		subs := impl.subscribers[key]
+		for i, s := range subs {
+			if s == sub {
+				impl.subscribers[key] = append(subs[:i], subs[i+1:]...)
+				break
+			}
+		}
+		close(sub.ch)
+	}
+	return sub.ch, cancel
+}
+
+func (impl *ResourceGroupServiceImpl) publish(event Event, parentResourceId int, groupType ResourceGroupType) {
+	impl.subMutex.Lock()
+	defer impl.subMutex.Unlock()
+	key := subscriptionKey(parentResourceId, groupType)
+	for _, sub := range impl.subscribers[key] {
+		select {
+		case sub.ch <- event:
+		default:
+			//subscriber is falling behind; drop rather than block the write path
+		}
+	}
+}
+
+func subscriptionKey(parentResourceId int, groupType ResourceGroupType) string {
+	return strconv.Itoa(parentResourceId) + ":" + string(groupType)
+}
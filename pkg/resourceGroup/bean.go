@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) 2020-2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resourceGroup
+
+import "github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+
+type ResourceGroupType string
+
+const (
+	APP_GROUP ResourceGroupType = "app-group"
+	ENV_GROUP ResourceGroupType = "env-group"
+)
+
+//CheckAuthBatchFunc enforces the given action against a batch of casbin objects, keyed by object in the result
+type CheckAuthBatchFunc func(token string, objects []string, action string) map[string]bool
+
+type ResourceGroupDto struct {
+	Id               int               `json:"id"`
+	Name             string            `json:"name" validate:"required"`
+	Description      string            `json:"description"`
+	ParentResourceId int               `json:"-"`
+	ResourceIds      []int             `json:"resourceIds"`
+	GroupType        ResourceGroupType `json:"groupType"`
+	//EnvironmentId and AppIds are retained for backward compatibility with the pre-existing app-group API shape
+	EnvironmentId int   `json:"environmentId,omitempty"`
+	AppIds        []int `json:"appIds,omitempty"`
+	UserId        int32 `json:"-"`
+
+	//Fingerprint is an optimistic-lock token that changes on every successful write; callers patching
+	//the group must echo back the value they last read
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	CheckAuthBatch CheckAuthBatchFunc `json:"-"`
+}
+
+//PatchOperation is a single JSONPath-scoped mutation against resourceIds, description or name
+type PatchOperation struct {
+	Path  string      `json:"path" validate:"required,oneof=resourceIds description name"`
+	Op    string      `json:"op" validate:"required,oneof=add remove replace"`
+	Value interface{} `json:"value" validate:"required"`
+}
+
+type MemberKind string
+
+const (
+	MemberKindUser  MemberKind = "user"
+	MemberKindGroup MemberKind = "group"
+	//MemberKindSelf lists every group of the parent resource and groupType with no membership filtering
+	//applied, as GetActiveResourceGroupList does; it is the default when memberKind is omitted
+	MemberKindSelf MemberKind = "self"
+)
+
+type ListOptions struct {
+	MemberKind   MemberKind
+	MemberId     int32
+	Permission   string
+	Limit        int
+	Offset       int
+	NameContains string
+}
+
+type ListResourceGroupsResult struct {
+	Groups     []*ResourceGroupDto `json:"groups"`
+	Total      int                 `json:"total"`
+	NextOffset int                 `json:"nextOffset,omitempty"`
+}
+
+//ImportRowResult reports what happened to one row of an ImportResourceGroups payload: a group
+//is "created" when no existing group matches by parent+groupType+name, "updated" when one does,
+//and "skipped" when the caller isn't authorized on one or more of its resourceIds
+type ImportRowResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ImportResult struct {
+	DryRun bool               `json:"dryRun"`
+	Rows   []*ImportRowResult `json:"rows"`
+}
+
+type EventOp string
+
+const (
+	EventOpCreated EventOp = "created"
+	EventOpUpdated EventOp = "updated"
+	EventOpDeleted EventOp = "deleted"
+)
+
+//Event is published by CreateResourceGroup/UpdateResourceGroup/DeleteResourceGroup after commit so
+//Subscribe()'d watchers can update a UI's group picker live instead of polling
+type Event struct {
+	Op          EventOp `json:"op"`
+	GroupId     int     `json:"groupId"`
+	ResourceIds []int   `json:"resourceIds"`
+	ActorUserId int32   `json:"actorUserId"`
+	Ts          int64   `json:"ts"`
+}
+
+//PermissionBulkItem represents one resourceId and the resourceIds under it whose per-action
+//permissions should be resolved together
+type PermissionBulkItem struct {
+	ResourceId  int   `json:"resourceId" validate:"required"`
+	ResourceIds []int `json:"resourceIds" validate:"required,min=1"`
+}
+
+type PermissionBulkRequest struct {
+	GroupType      ResourceGroupType   `json:"-"`
+	Items          []*PermissionBulkItem `json:"items" validate:"required,min=1,dive"`
+	CheckAuthBatch CheckAuthBatchFunc    `json:"-"`
+}
+
+//PermissionBulkResult mirrors a PermissionBulkItem, resolving each id under ResourceIds to a map of action -> allowed
+type PermissionBulkResult struct {
+	ResourceId int                      `json:"resourceId"`
+	Results    map[int]map[string]bool  `json:"results"`
+}
+
+//permissionBulkActions is the fixed set of actions resolved per resource for the bulk permission-check endpoint
+var permissionBulkActions = []string{casbin.ActionGet, casbin.ActionTrigger, casbin.ActionUpdate, casbin.ActionDelete}